@@ -1,16 +1,10 @@
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"log"
-	"net/http"
-	"os"
-	"strings"
-	"sync"
 	"time"
 )
 
@@ -37,7 +31,9 @@ Authentication Testing Tool
 const helpText = `
 DESCRIPTION:
   Auth Check is a specialized tool for comparing HTTP responses with different authentication methods.
-  It helps identify potential authentication bypass vulnerabilities by comparing responses between:
+  It helps identify potential authentication bypass vulnerabilities by comparing responses between
+  two AuthProfiles (see -profile), which describe arbitrary header/query/signing setups. Four
+  built-in profile pairs are available as shorthand via -mode:
   1. Cookies and No Cookies
   2. Two different Cookie headers
   3. Bearer token and No Bearer token
@@ -45,20 +41,22 @@ DESCRIPTION:
 
 FEATURES:
   - Tests both GET and POST methods
-  - Concurrent request processing
+  - Bounded worker pool with per-host rate limiting
   - Progress bar visualization
   - Filters out static files (.js, .map, .svg)
-  - Automatic retry mechanism for failed requests
+  - Jittered exponential backoff retries for connection errors, 429s, and 5xxs
   - Detailed response comparison including:
     • Response status codes
     • Response body sizes
-    • Side-by-side comparison
+    • Fuzzy response similarity (Levenshtein, token-set Jaccard, simhash)
+    • Side-by-side comparison of differing lines
 
 USAGE:
   auth_check [options] -f <file_with_endpoints>
 
 OPTIONS:
-  -f <file>        File containing endpoints (one per line)
+  -f <file>        Endpoint source: plain text (one URL per line), .har, OpenAPI/Swagger
+                   (.json/.yaml with an "openapi"/"swagger" key), or a Burp XML export (.xml)
   -version         Show version information
   -mode <number>   Operation mode (1-4):
                    1: Cookies -> No Cookies
@@ -69,6 +67,19 @@ OPTIONS:
   -c2 <cookie>     Second cookie header (for mode 2)
   -t1 <token>      First bearer token
   -t2 <token>      Second bearer token (for mode 4)
+  -o <file>        Write machine-readable results to this file as the scan runs
+  -format <fmt>    Report format when -o is set: json, jsonl, csv (default "jsonl")
+  -threshold <n>   Minimum response similarity (0-1) to report as a bypass (default 0.95)
+  -profile <file>  AuthProfile file (YAML or JSON); pass twice, once per side of the
+                   comparison. Describes headers to set/strip, query params, a body
+                   template, and an optional HMAC signing key. Overrides -mode.
+  -workers <n>     Maximum number of concurrent requests (default 20)
+  -rps <n>         Per-host requests per second, 0 = unlimited (default 0)
+  -retries <n>     Retries for connection errors and 429/5xx responses (default 2)
+  -proxy <url>     Upstream proxy URL, e.g. http://127.0.0.1:8080 for Burp/ZAP
+  -insecure        Skip TLS certificate verification (for MITM proxy CAs)
+  -timeout <dur>   Per-request timeout (default 10s)
+  -capture <dir>   Write raw .req/.resp files for every reported bypass to this directory
 
 EXAMPLES:
   Compare with/without cookie:
@@ -84,8 +95,11 @@ EXAMPLES:
     auth_check -f endpoints.txt -mode 4 -t1 "eyJ0eXAi..." -t2 "eyKhbGci..."
 
 OUTPUT:
-  The tool reports endpoints where both requests return HTTP 200 status codes,
-  showing the differences in response sizes that might indicate potential issues.
+  The tool reports endpoints where both requests return HTTP 200 status codes
+  and the response bodies are similar enough (see -threshold) to be the same
+  underlying page, rather than requiring an exact byte-size match.
+  Pass -o to additionally stream every result (including skips and errors) to
+  disk as JSON, JSONL or CSV for downstream tooling.
 `
 
 type Result struct {
@@ -95,24 +109,22 @@ type Result struct {
 	StatusCode2  int
 	Size1        int64
 	Size2        int64
+	HeaderHash1  string
+	HeaderHash2  string
+	Similarity   float64
+	DiffLines    []string
+	RetryCount   int
+	RateLimited  bool
+	RawReq1      []byte
+	RawResp1     []byte
+	RawReq2      []byte
+	RawResp2     []byte
+	Skipped      bool
 	Error        error
 	Description1 string
 	Description2 string
 }
 
-var (
-	client = &http.Client{
-		Timeout: 10 * time.Second,
-		Transport: &http.Transport{
-			MaxIdleConns:        100,
-			IdleConnTimeout:     90 * time.Second,
-			DisableCompression:  true,
-			MaxConnsPerHost:     10,
-			MaxIdleConnsPerHost: 10,
-		},
-	}
-)
-
 // ANSI color codes
 const (
 	colorReset  = "\033[0m"
@@ -125,121 +137,6 @@ func printColored(color, text string) {
 	fmt.Printf("%s%s%s", color, text, colorReset)
 }
 
-func makeRequest(url, method string, headers map[string]string) (int, int64, error) {
-	req, err := http.NewRequest(method, url, nil)
-	if err != nil {
-		return 0, 0, err
-	}
-
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return 0, 0, nil // Ignore all errors
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, 0, nil // Ignore all errors
-	}
-
-	return resp.StatusCode, int64(len(body)), nil
-}
-
-func processEndpoint(endpoint, method string, headers1, headers2 map[string]string, desc1, desc2 string) Result {
-	if strings.HasSuffix(endpoint, ".js") || strings.HasSuffix(endpoint, ".map") || strings.HasSuffix(endpoint, ".svg") {
-		return Result{Error: fmt.Errorf("skipped static file")}
-	}
-
-	status1, size1, err := makeRequest(endpoint, method, headers1)
-	if err != nil {
-		return Result{Error: err}
-	}
-
-	status2, size2, err := makeRequest(endpoint, method, headers2)
-	if err != nil {
-		return Result{Error: err}
-	}
-
-	return Result{
-		Endpoint:     endpoint,
-		Method:       method,
-		StatusCode1:  status1,
-		StatusCode2:  status2,
-		Size1:        size1,
-		Size2:        size2,
-		Description1: desc1,
-		Description2: desc2,
-	}
-}
-
-func processFile(filename string, headers1, headers2 map[string]string, desc1, desc2 string) {
-	file, err := os.Open(filename)
-	if err != nil {
-		fmt.Printf("Error opening file: %v\n", err)
-		return
-	}
-	defer file.Close()
-
-	var endpoints []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		endpoints = append(endpoints, strings.TrimSpace(scanner.Text()))
-	}
-
-	results := make(chan Result)
-	var wg sync.WaitGroup
-
-	// Process endpoints concurrently
-	for _, endpoint := range endpoints {
-		wg.Add(2) // One for GET, one for POST
-		go func(ep string) {
-			defer wg.Done()
-			results <- processEndpoint(ep, "GET", headers1, headers2, desc1, desc2)
-		}(endpoint)
-		go func(ep string) {
-			defer wg.Done()
-			results <- processEndpoint(ep, "POST", headers1, headers2, desc1, desc2)
-		}(endpoint)
-	}
-
-	// Close results channel when all goroutines are done
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Process results as they come in
-	total := len(endpoints) * 2
-	count := 0
-	for result := range results {
-		count++
-		printProgress(count, total)
-
-		if result.Error != nil {
-			continue
-		}
-
-		// Only report if both status codes are 200 AND response sizes are identical
-		if result.StatusCode1 == 200 && result.StatusCode2 == 200 && result.Size1 == result.Size2 {
-			// Clear the current line and print the match
-			fmt.Printf("\r\033[K") // Clear the entire line
-			printColored(colorGreen, fmt.Sprintf("Potential Auth Bypass Found!\n"))
-			printColored(colorGreen, fmt.Sprintf("Endpoint: %s [%s]\n", result.Endpoint, result.Method))
-			printColored(colorYellow, fmt.Sprintf("%s: %d (%d bytes)\n", result.Description1, result.StatusCode1, result.Size1))
-			printColored(colorYellow, fmt.Sprintf("%s: %d (%d bytes)\n", result.Description2, result.StatusCode2, result.Size2))
-			fmt.Printf("\n") // Add spacing between matches
-			// Print a new progress bar
-			printProgress(count, total)
-		}
-	}
-	fmt.Printf("\r\033[K") // Clear the final progress bar
-	fmt.Printf("Done.\n")
-}
-
 func printProgress(current, total int) {
 	width := 50
 	percentage := float64(current) / float64(total)
@@ -258,13 +155,25 @@ func printProgress(current, total int) {
 
 func main() {
 	// Parse command line flags
-	fileFlag := flag.String("f", "", "File containing endpoints (one per line)")
+	fileFlag := flag.String("f", "", "Endpoint source: plain text (one URL per line), .har, OpenAPI/Swagger (.json/.yaml), or Burp XML export (.xml)")
 	modeFlag := flag.Int("mode", 0, "Operation mode (1-4)")
 	cookie1Flag := flag.String("c1", "", "First cookie header")
 	cookie2Flag := flag.String("c2", "", "Second cookie header")
 	token1Flag := flag.String("t1", "", "First bearer token")
 	token2Flag := flag.String("t2", "", "Second bearer token")
+	outputFlag := flag.String("o", "", "Write machine-readable results to this file as the scan runs")
+	formatFlag := flag.String("format", "jsonl", "Report format when -o is set: json, jsonl, csv")
+	thresholdFlag := flag.Float64("threshold", 0.95, "Minimum response similarity (0-1) to report as a potential bypass")
+	workersFlag := flag.Int("workers", 20, "Maximum number of concurrent requests")
+	rpsFlag := flag.Float64("rps", 0, "Per-host requests per second (0 = unlimited)")
+	retriesFlag := flag.Int("retries", 2, "Retries for connection errors and 429/5xx responses (jittered exponential backoff)")
+	proxyFlag := flag.String("proxy", "", "Upstream proxy URL (e.g. http://127.0.0.1:8080 for Burp/ZAP)")
+	insecureFlag := flag.Bool("insecure", false, "Skip TLS certificate verification (for MITM proxy CAs)")
+	timeoutFlag := flag.Duration("timeout", 10*time.Second, "Per-request timeout")
+	captureFlag := flag.String("capture", "", "Write raw .req/.resp files for every reported bypass to this directory")
 	versionFlag := flag.Bool("version", false, "Show version information")
+	var profileFiles profileFlags
+	flag.Var(&profileFiles, "profile", "AuthProfile file (YAML or JSON); pass twice, once per side of the comparison")
 	flag.Parse()
 
 	if *versionFlag {
@@ -272,60 +181,56 @@ func main() {
 		return
 	}
 
-	if *fileFlag == "" || *modeFlag == 0 {
+	if *fileFlag == "" || (*modeFlag == 0 && len(profileFiles) == 0) {
 		fmt.Printf(banner, version)
-		fmt.Println(helpText)
+		fmt.Print(helpText)
 		return
 	}
 
-	var headers1, headers2 map[string]string
-	var desc1, desc2 string
+	var profile1, profile2 AuthProfile
 
-	switch *modeFlag {
-	case 1:
-		if *cookie1Flag == "" {
-			fmt.Println("Error: Cookie (-c1) is required for mode 1")
+	switch {
+	case len(profileFiles) > 0:
+		if len(profileFiles) != 2 {
+			fmt.Println("Error: exactly two -profile flags are required")
 			return
 		}
-		headers1 = map[string]string{"Cookie": *cookie1Flag}
-		headers2 = map[string]string{}
-		desc1 = "With Cookie"
-		desc2 = "Without Cookie"
-
-	case 2:
-		if *cookie1Flag == "" || *cookie2Flag == "" {
-			fmt.Println("Error: Both cookies (-c1 and -c2) are required for mode 2")
+		p1, err := LoadAuthProfile(profileFiles[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
 			return
 		}
-		headers1 = map[string]string{"Cookie": *cookie1Flag}
-		headers2 = map[string]string{"Cookie": *cookie2Flag}
-		desc1 = "Cookie 1"
-		desc2 = "Cookie 2"
+		p2, err := LoadAuthProfile(profileFiles[1])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		profile1, profile2 = *p1, *p2
 
-	case 3:
-		if *token1Flag == "" {
-			fmt.Println("Error: Bearer token (-t1) is required for mode 3")
+	default:
+		var err error
+		profile1, profile2, err = builtinProfilePair(*modeFlag, *cookie1Flag, *cookie2Flag, *token1Flag, *token2Flag)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
 			return
 		}
-		headers1 = map[string]string{"Authorization": "Bearer " + *token1Flag}
-		headers2 = map[string]string{}
-		desc1 = "With Token"
-		desc2 = "Without Token"
+	}
 
-	case 4:
-		if *token1Flag == "" || *token2Flag == "" {
-			fmt.Println("Error: Both tokens (-t1 and -t2) are required for mode 4")
+	var reporter Reporter
+	if *outputFlag != "" {
+		var err error
+		reporter, err = NewReporter(*formatFlag, *outputFlag)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
 			return
 		}
-		headers1 = map[string]string{"Authorization": "Bearer " + *token1Flag}
-		headers2 = map[string]string{"Authorization": "Bearer " + *token2Flag}
-		desc1 = "Token 1"
-		desc2 = "Token 2"
+	}
 
-	default:
-		fmt.Println("Error: Invalid mode. Must be 1-4")
+	httpClient, err := buildClient(*proxyFlag, *insecureFlag, *timeoutFlag)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		return
 	}
 
-	processFile(*fileFlag, headers1, headers2, desc1, desc2)
+	processSource(httpClient, *fileFlag, profile1, profile2, reporter, *thresholdFlag, *workersFlag, *rpsFlag, *retriesFlag, *captureFlag)
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAuthProfileApplyHeadersQueryAndStrip(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://example.com/path?existing=1", nil)
+	req.Header.Set("Cookie", "session=old")
+
+	profile := AuthProfile{
+		Headers:      map[string]string{"Authorization": "Bearer token"},
+		StripHeaders: []string{"Cookie"},
+		Query:        map[string]string{"debug": "true"},
+	}
+	profile.Apply(req)
+
+	if got := req.Header.Get("Authorization"); got != "Bearer token" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer token")
+	}
+	if got := req.Header.Get("Cookie"); got != "" {
+		t.Errorf("Cookie = %q, want stripped", got)
+	}
+	if got := req.URL.Query().Get("debug"); got != "true" {
+		t.Errorf("query debug = %q, want true", got)
+	}
+}
+
+func TestAuthProfileApplySignsWithHMAC(t *testing.T) {
+	profile := AuthProfile{HMACSecret: "s3cr3t"}
+
+	req1, _ := http.NewRequest("GET", "http://example.com/path?a=1", nil)
+	profile.Apply(req1)
+	sig1 := req1.Header.Get("X-Signature")
+	if sig1 == "" {
+		t.Fatal("expected X-Signature header to be set")
+	}
+
+	// Same method/path/query must reproduce the same signature.
+	req2, _ := http.NewRequest("GET", "http://example.com/path?a=1", nil)
+	profile.Apply(req2)
+	if sig2 := req2.Header.Get("X-Signature"); sig2 != sig1 {
+		t.Errorf("signature not deterministic: %q != %q", sig1, sig2)
+	}
+
+	// A different request line must produce a different signature.
+	req3, _ := http.NewRequest("GET", "http://example.com/path?a=2", nil)
+	profile.Apply(req3)
+	if sig3 := req3.Header.Get("X-Signature"); sig3 == sig1 {
+		t.Errorf("expected different query to change the signature")
+	}
+}
+
+func TestAuthProfileRequestBody(t *testing.T) {
+	ep := Endpoint{Body: []byte(`{"from":"endpoint"}`)}
+
+	if got := (AuthProfile{}).RequestBody(ep); string(got) != `{"from":"endpoint"}` {
+		t.Errorf("no BodyTemplate: RequestBody = %q, want the endpoint's own body", got)
+	}
+
+	profile := AuthProfile{BodyTemplate: `{"from":"template"}`}
+	if got := profile.RequestBody(ep); string(got) != `{"from":"template"}` {
+		t.Errorf("with BodyTemplate: RequestBody = %q, want the template to override the endpoint body", got)
+	}
+}
+
+func TestAuthProfileApplyCustomHMACHeader(t *testing.T) {
+	profile := AuthProfile{HMACSecret: "s3cr3t", HMACHeader: "X-Custom-Sig"}
+	req, _ := http.NewRequest("GET", "http://example.com/path", nil)
+	profile.Apply(req)
+
+	if req.Header.Get("X-Custom-Sig") == "" {
+		t.Error("expected signature under the configured header name")
+	}
+	if req.Header.Get("X-Signature") != "" {
+		t.Error("did not expect the default header to be set alongside a custom one")
+	}
+}
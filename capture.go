@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var captureNameRe = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// captureName turns an endpoint+method into a filesystem-safe basename.
+func captureName(result Result) string {
+	name := captureNameRe.ReplaceAllString(result.Endpoint, "_")
+	name = fmt.Sprintf("%s_%s", result.Method, name)
+	if len(name) > 150 {
+		name = name[:150]
+	}
+	return name
+}
+
+// writeCapture writes the raw request/response pair for both sides of a
+// reported bypass to captureDir, so the finding can be replayed and fuzzed
+// directly (e.g. in Burp) without re-running the scan.
+func writeCapture(captureDir string, result Result) error {
+	if err := os.MkdirAll(captureDir, 0o755); err != nil {
+		return fmt.Errorf("creating capture dir: %w", err)
+	}
+
+	base := filepath.Join(captureDir, captureName(result))
+	files := map[string][]byte{
+		base + ".1.req":  result.RawReq1,
+		base + ".1.resp": result.RawResp1,
+		base + ".2.req":  result.RawReq2,
+		base + ".2.resp": result.RawResp2,
+	}
+	for path, data := range files {
+		if len(data) == 0 {
+			continue
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", path, err)
+		}
+	}
+	return nil
+}
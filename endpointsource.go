@@ -0,0 +1,398 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Endpoint is one request to test, as produced by an EndpointSource. Unlike
+// the original plain-text format (one URL per line, always tried as both
+// GET and POST), sources that know more about the request - HAR captures,
+// OpenAPI specs, Burp exports - can supply the real method, body and
+// content type.
+type Endpoint struct {
+	URL         string
+	Method      string
+	Body        []byte
+	ContentType string
+}
+
+// EndpointSource loads the corpus of endpoints to test from a file.
+type EndpointSource interface {
+	Load(path string) ([]Endpoint, error)
+}
+
+// LoadEndpoints picks an EndpointSource based on the file's extension and
+// content, and loads it. Supported formats: HAR 1.2 (.har), Burp Suite XML
+// exports (.xml), OpenAPI 3 / Swagger 2 specs (.json/.yaml/.yml containing
+// an "openapi" or "swagger" key), and the original plain-text format
+// (anything else: one URL per line, tested as both GET and POST).
+func LoadEndpoints(path string) ([]Endpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); {
+	case ext == ".har":
+		return harSource{}.parse(data)
+	case ext == ".xml":
+		return burpXMLSource{}.parse(data)
+	case (ext == ".json" || ext == ".yaml" || ext == ".yml") && looksLikeOpenAPI(data):
+		return openAPISource{}.parse(data)
+	default:
+		return plainTextSource{}.parse(data)
+	}
+}
+
+func looksLikeOpenAPI(data []byte) bool {
+	return bytes.Contains(data, []byte("openapi")) || bytes.Contains(data, []byte("swagger"))
+}
+
+// --- plain text: one URL per line, tried as both GET and POST ---
+
+type plainTextSource struct{}
+
+func (plainTextSource) parse(data []byte) ([]Endpoint, error) {
+	var endpoints []Endpoint
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		endpoints = append(endpoints, Endpoint{URL: line, Method: "GET"})
+		endpoints = append(endpoints, Endpoint{URL: line, Method: "POST"})
+	}
+	return endpoints, scanner.Err()
+}
+
+// --- HAR 1.2 ---
+
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				Method   string `json:"method"`
+				URL      string `json:"url"`
+				PostData *struct {
+					MimeType string `json:"mimeType"`
+					Text     string `json:"text"`
+				} `json:"postData"`
+			} `json:"request"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+type harSource struct{}
+
+func (harSource) parse(data []byte) ([]Endpoint, error) {
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, fmt.Errorf("parsing HAR: %w", err)
+	}
+
+	var endpoints []Endpoint
+	for _, entry := range har.Log.Entries {
+		ep := Endpoint{URL: entry.Request.URL, Method: entry.Request.Method}
+		if entry.Request.PostData != nil {
+			ep.Body = []byte(entry.Request.PostData.Text)
+			ep.ContentType = entry.Request.PostData.MimeType
+		}
+		endpoints = append(endpoints, ep)
+	}
+	return endpoints, nil
+}
+
+// --- Burp Suite XML export ---
+
+type burpItems struct {
+	Items []struct {
+		URL     string `xml:"url"`
+		Method  string `xml:"method"`
+		Request struct {
+			Base64  string `xml:"base64,attr"`
+			Content string `xml:",chardata"`
+		} `xml:"request"`
+	} `xml:"item"`
+}
+
+type burpXMLSource struct{}
+
+func (burpXMLSource) parse(data []byte) ([]Endpoint, error) {
+	var items burpItems
+	if err := xml.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("parsing Burp XML export: %w", err)
+	}
+
+	var endpoints []Endpoint
+	for _, item := range items.Items {
+		ep := Endpoint{URL: item.URL, Method: item.Method}
+
+		raw := []byte(item.Request.Content)
+		if strings.EqualFold(item.Request.Base64, "true") {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(item.Request.Content))
+			if err == nil {
+				raw = decoded
+			}
+		}
+		ep.Body, ep.ContentType = splitRawHTTPRequest(raw)
+
+		endpoints = append(endpoints, ep)
+	}
+	return endpoints, nil
+}
+
+var contentTypeHeaderRe = regexp.MustCompile(`(?im)^content-type:\s*(.+)\r?$`)
+
+// splitRawHTTPRequest pulls the body and Content-Type out of a raw HTTP
+// request message (headers + CRLFCRLF + body), as captured by Burp.
+func splitRawHTTPRequest(raw []byte) (body []byte, contentType string) {
+	sep := []byte("\r\n\r\n")
+	idx := bytes.Index(raw, sep)
+	if idx < 0 {
+		sep = []byte("\n\n")
+		idx = bytes.Index(raw, sep)
+	}
+	if idx < 0 {
+		return nil, ""
+	}
+
+	head := raw[:idx]
+	body = raw[idx+len(sep):]
+	if m := contentTypeHeaderRe.FindSubmatch(head); m != nil {
+		contentType = strings.TrimSpace(string(m[1]))
+	}
+	return body, contentType
+}
+
+// --- OpenAPI 3 / Swagger 2 ---
+
+type openAPISource struct{}
+
+func (openAPISource) parse(data []byte) ([]Endpoint, error) {
+	var spec map[string]interface{}
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI/Swagger spec: %w", err)
+	}
+
+	baseURL := ""
+	if servers, ok := spec["servers"].([]interface{}); ok && len(servers) > 0 {
+		if server, ok := servers[0].(map[string]interface{}); ok {
+			if u, ok := server["url"].(string); ok {
+				baseURL = strings.TrimSuffix(u, "/")
+			}
+		}
+	}
+	if baseURL == "" {
+		if host, ok := spec["host"].(string); ok && host != "" {
+			scheme := "https"
+			if schemes, ok := spec["schemes"].([]interface{}); ok && len(schemes) > 0 {
+				if s, ok := schemes[0].(string); ok {
+					scheme = s
+				}
+			}
+			basePath, _ := spec["basePath"].(string)
+			baseURL = scheme + "://" + host + basePath
+		}
+	}
+
+	paths, _ := spec["paths"].(map[string]interface{})
+	var endpoints []Endpoint
+	for path, rawItem := range paths {
+		item, ok := rawItem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pathParams, _ := item["parameters"].([]interface{})
+
+		for _, method := range []string{"get", "post", "put", "patch", "delete"} {
+			rawOp, ok := item[method]
+			if !ok {
+				continue
+			}
+			op, ok := rawOp.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			params := append(append([]interface{}{}, pathParams...), asInterfaceSlice(op["parameters"])...)
+			resolvedPath, query := resolveOpenAPIParams(path, params)
+
+			url := baseURL + resolvedPath
+			if query != "" {
+				url += "?" + query
+			}
+
+			ep := Endpoint{URL: url, Method: strings.ToUpper(method)}
+			if reqBody, ok := op["requestBody"].(map[string]interface{}); ok {
+				ep.Body, ep.ContentType = synthesizeOpenAPIBody(reqBody)
+			}
+			endpoints = append(endpoints, ep)
+		}
+	}
+	return endpoints, nil
+}
+
+func asInterfaceSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+// resolveOpenAPIParams fills {param} placeholders in path from each
+// parameter's example/default (falling back to "1"), and builds a query
+// string from "in: query" parameters the same way.
+func resolveOpenAPIParams(path string, params []interface{}) (resolvedPath, query string) {
+	resolvedPath = path
+	var queryParts []string
+
+	for _, raw := range params {
+		param, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		if name == "" {
+			continue
+		}
+		value := openAPIExampleValue(param)
+
+		switch in {
+		case "path":
+			resolvedPath = strings.ReplaceAll(resolvedPath, "{"+name+"}", value)
+		case "query":
+			queryParts = append(queryParts, name+"="+value)
+		}
+	}
+
+	return resolvedPath, strings.Join(queryParts, "&")
+}
+
+// openAPIExampleValue picks a placeholder value for a parameter or schema
+// property: its example, then its default, then a type-appropriate filler.
+// The result is always returned as a string (callers that build URLs/query
+// strings need that), but is valid JSON source for its schema type, so
+// toJSON can emit it unquoted for numeric/boolean properties.
+func openAPIExampleValue(field map[string]interface{}) string {
+	if example, ok := field["example"]; ok {
+		return fmt.Sprint(example)
+	}
+	schema, _ := field["schema"].(map[string]interface{})
+	if schema == nil {
+		schema = field
+	}
+	if example, ok := schema["example"]; ok {
+		return fmt.Sprint(example)
+	}
+	if def, ok := schema["default"]; ok {
+		return fmt.Sprint(def)
+	}
+	switch fmt.Sprint(schema["type"]) {
+	case "integer", "number":
+		return "1"
+	case "boolean":
+		return "true"
+	default:
+		return "test"
+	}
+}
+
+// openAPIPropertyType resolves a schema property's declared "type", used to
+// decide whether synthesizeFromSchema's placeholder belongs in the JSON body
+// as a number/boolean literal or a quoted string.
+func openAPIPropertyType(prop map[string]interface{}) string {
+	return fmt.Sprint(prop["type"])
+}
+
+// synthesizeOpenAPIBody builds a minimal JSON body from the first JSON
+// media type in a requestBody's content map, filling each property with its
+// example/default/type-appropriate placeholder.
+func synthesizeOpenAPIBody(reqBody map[string]interface{}) ([]byte, string) {
+	content, _ := reqBody["content"].(map[string]interface{})
+	for mimeType, rawMedia := range content {
+		if !strings.Contains(mimeType, "json") {
+			continue
+		}
+		media, _ := rawMedia.(map[string]interface{})
+		schema, _ := media["schema"].(map[string]interface{})
+		obj := synthesizeFromSchema(schema)
+		return []byte(toJSON(obj)), mimeType
+	}
+	return nil, ""
+}
+
+// jsonField is one property of a synthesized body: its placeholder value
+// plus the declared schema type, so toJSON knows whether to quote it.
+type jsonField struct {
+	value string
+	typ   string
+}
+
+func synthesizeFromSchema(schema map[string]interface{}) map[string]jsonField {
+	obj := map[string]jsonField{}
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name, rawProp := range properties {
+		prop, ok := rawProp.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		obj[name] = jsonField{value: openAPIExampleValue(prop), typ: openAPIPropertyType(prop)}
+	}
+	return obj
+}
+
+// toJSON is a tiny dependency-free encoder good enough for the flat objects
+// synthesizeFromSchema produces. Integer/number/boolean fields are emitted
+// as JSON literals rather than quoted strings, so strict request validation
+// sees the right wire type instead of rejecting e.g. "age": "1" - but only
+// when the placeholder value actually parses as that type, so an unusual
+// spec (e.g. a string "example" on an "integer" field) still falls back to
+// a quoted string instead of emitting invalid JSON.
+func toJSON(obj map[string]jsonField) string {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	first := true
+	for k, field := range obj {
+		if !first {
+			sb.WriteByte(',')
+		}
+		first = false
+		sb.WriteString(strconv.Quote(k))
+		sb.WriteByte(':')
+		sb.WriteString(jsonLiteral(field))
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// jsonLiteral renders a field as an unquoted JSON literal when both its
+// declared type and its actual value agree it's safe to, otherwise as a
+// quoted JSON string. Bool values are re-rendered via FormatBool rather
+// than passed through verbatim, so loose spellings like "1"/"0" come out
+// as the canonical true/false rather than a bare number.
+func jsonLiteral(field jsonField) string {
+	switch field.typ {
+	case "integer", "number":
+		if n, err := strconv.ParseFloat(field.value, 64); err == nil && !math.IsNaN(n) && !math.IsInf(n, 0) {
+			return field.value
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(field.value); err == nil {
+			return strconv.FormatBool(b)
+		}
+	}
+	return strconv.Quote(field.value)
+}
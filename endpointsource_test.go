@@ -0,0 +1,342 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlainTextSourceParse(t *testing.T) {
+	data := []byte("http://example.com/a\n\n  http://example.com/b  \n")
+	endpoints, err := plainTextSource{}.parse(data)
+	if err != nil {
+		t.Fatalf("parse returned error: %v", err)
+	}
+
+	want := []Endpoint{
+		{URL: "http://example.com/a", Method: "GET"},
+		{URL: "http://example.com/a", Method: "POST"},
+		{URL: "http://example.com/b", Method: "GET"},
+		{URL: "http://example.com/b", Method: "POST"},
+	}
+	if len(endpoints) != len(want) {
+		t.Fatalf("got %d endpoints, want %d: %+v", len(endpoints), len(want), endpoints)
+	}
+	for i, ep := range endpoints {
+		if ep.URL != want[i].URL || ep.Method != want[i].Method {
+			t.Errorf("endpoint %d = %+v, want %+v", i, ep, want[i])
+		}
+	}
+}
+
+func TestHarSourceParse(t *testing.T) {
+	har := []byte(`{
+		"log": {
+			"entries": [
+				{"request": {"method": "GET", "url": "http://example.com/list"}},
+				{"request": {"method": "POST", "url": "http://example.com/create",
+					"postData": {"mimeType": "application/json", "text": "{\"a\":1}"}}}
+			]
+		}
+	}`)
+
+	endpoints, err := harSource{}.parse(har)
+	if err != nil {
+		t.Fatalf("parse returned error: %v", err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("got %d endpoints, want 2: %+v", len(endpoints), endpoints)
+	}
+
+	if endpoints[0].Method != "GET" || endpoints[0].URL != "http://example.com/list" || len(endpoints[0].Body) != 0 {
+		t.Errorf("endpoint 0 = %+v, want a bodyless GET", endpoints[0])
+	}
+	if endpoints[1].Method != "POST" || endpoints[1].URL != "http://example.com/create" {
+		t.Errorf("endpoint 1 = %+v, want the POST /create entry", endpoints[1])
+	}
+	if string(endpoints[1].Body) != `{"a":1}` || endpoints[1].ContentType != "application/json" {
+		t.Errorf("endpoint 1 body/contentType = %q/%q, want postData.text/mimeType", endpoints[1].Body, endpoints[1].ContentType)
+	}
+}
+
+func TestBurpXMLSourceParse(t *testing.T) {
+	rawRequest := "POST /login HTTP/1.1\r\nHost: example.com\r\nContent-Type: application/json\r\n\r\n{\"user\":\"a\"}"
+	encoded := base64.StdEncoding.EncodeToString([]byte(rawRequest))
+
+	xmlData := []byte(`<?xml version="1.0"?>
+<items>
+  <item>
+    <url>http://example.com/login</url>
+    <method>POST</method>
+    <request base64="true">` + encoded + `</request>
+  </item>
+  <item>
+    <url>http://example.com/plain</url>
+    <method>GET</method>
+    <request base64="false">GET /plain HTTP/1.1
+Host: example.com
+
+</request>
+  </item>
+</items>`)
+
+	endpoints, err := burpXMLSource{}.parse(xmlData)
+	if err != nil {
+		t.Fatalf("parse returned error: %v", err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("got %d endpoints, want 2: %+v", len(endpoints), endpoints)
+	}
+
+	if endpoints[0].URL != "http://example.com/login" || endpoints[0].Method != "POST" {
+		t.Errorf("endpoint 0 = %+v, want the decoded base64 login item", endpoints[0])
+	}
+	if string(endpoints[0].Body) != `{"user":"a"}` || endpoints[0].ContentType != "application/json" {
+		t.Errorf("endpoint 0 body/contentType = %q/%q, want the body/Content-Type from the decoded request", endpoints[0].Body, endpoints[0].ContentType)
+	}
+
+	if endpoints[1].URL != "http://example.com/plain" || endpoints[1].Method != "GET" {
+		t.Errorf("endpoint 1 = %+v, want the plain (non-base64) item", endpoints[1])
+	}
+}
+
+func TestOpenAPISourceParseOpenAPI3(t *testing.T) {
+	spec := []byte(`
+openapi: "3.0.0"
+servers:
+  - url: http://example.com/api
+paths:
+  /widgets/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          example: 42
+        - name: verbose
+          in: query
+          example: true
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              properties:
+                name:
+                  type: string
+`)
+
+	endpoints, err := openAPISource{}.parse(spec)
+	if err != nil {
+		t.Fatalf("parse returned error: %v", err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("got %d endpoints, want 2: %+v", len(endpoints), endpoints)
+	}
+
+	var get, post *Endpoint
+	for i := range endpoints {
+		switch endpoints[i].Method {
+		case "GET":
+			get = &endpoints[i]
+		case "POST":
+			post = &endpoints[i]
+		}
+	}
+	if get == nil || get.URL != "http://example.com/api/widgets/42?verbose=true" {
+		t.Errorf("GET endpoint = %+v, want path param and query resolved against the spec's server URL", get)
+	}
+	if post == nil || post.ContentType != "application/json" {
+		t.Fatalf("POST endpoint = %+v, want a synthesized JSON body", post)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(post.Body, &decoded); err != nil {
+		t.Errorf("POST body %q is not valid JSON: %v", post.Body, err)
+	}
+}
+
+func TestOpenAPISourceParseSwagger2(t *testing.T) {
+	spec := []byte(`{
+		"swagger": "2.0",
+		"host": "example.com",
+		"schemes": ["https"],
+		"basePath": "/v1",
+		"paths": {
+			"/ping": {
+				"get": {}
+			}
+		}
+	}`)
+
+	endpoints, err := openAPISource{}.parse(spec)
+	if err != nil {
+		t.Fatalf("parse returned error: %v", err)
+	}
+	if len(endpoints) != 1 || endpoints[0].URL != "https://example.com/v1/ping" || endpoints[0].Method != "GET" {
+		t.Fatalf("endpoints = %+v, want a single GET built from host+schemes+basePath", endpoints)
+	}
+}
+
+func TestLoadEndpointsDispatchesByExtensionAndContent(t *testing.T) {
+	dir := t.TempDir()
+
+	txtPath := filepath.Join(dir, "endpoints.txt")
+	if err := os.WriteFile(txtPath, []byte("http://example.com/x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if endpoints, err := LoadEndpoints(txtPath); err != nil || len(endpoints) != 2 {
+		t.Errorf("plain text: got (%+v, %v), want 2 endpoints (GET+POST), nil error", endpoints, err)
+	}
+
+	harPath := filepath.Join(dir, "capture.har")
+	harData := []byte(`{"log":{"entries":[{"request":{"method":"GET","url":"http://example.com/h"}}]}}`)
+	if err := os.WriteFile(harPath, harData, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if endpoints, err := LoadEndpoints(harPath); err != nil || len(endpoints) != 1 || endpoints[0].URL != "http://example.com/h" {
+		t.Errorf("HAR: got (%+v, %v), want the single HAR entry", endpoints, err)
+	}
+
+	yamlPath := filepath.Join(dir, "spec.yaml")
+	yamlData := []byte("openapi: \"3.0.0\"\nservers:\n  - url: http://example.com\npaths:\n  /ping:\n    get: {}\n")
+	if err := os.WriteFile(yamlPath, yamlData, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if endpoints, err := LoadEndpoints(yamlPath); err != nil || len(endpoints) != 1 || endpoints[0].URL != "http://example.com/ping" {
+		t.Errorf("OpenAPI YAML: got (%+v, %v), want the single /ping GET", endpoints, err)
+	}
+
+	// A .yaml file without an openapi/swagger key must NOT be routed to the
+	// OpenAPI parser - it should fall through to plain text (and harmlessly
+	// produce one GET+POST pair per non-empty line, however nonsensical).
+	plainYAMLPath := filepath.Join(dir, "notes.yaml")
+	if err := os.WriteFile(plainYAMLPath, []byte("just: notes\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if endpoints, err := LoadEndpoints(plainYAMLPath); err != nil || len(endpoints) != 2 {
+		t.Errorf("non-OpenAPI YAML: got (%+v, %v), want plain-text fallback (2 endpoints)", endpoints, err)
+	}
+}
+
+func TestSynthesizeOpenAPIBodyEmitsTypedLiterals(t *testing.T) {
+	reqBody := map[string]interface{}{
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"properties": map[string]interface{}{
+						"age":    map[string]interface{}{"type": "integer"},
+						"active": map[string]interface{}{"type": "boolean"},
+						"name":   map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+
+	body, mimeType := synthesizeOpenAPIBody(reqBody)
+	if mimeType != "application/json" {
+		t.Fatalf("mimeType = %q, want application/json", mimeType)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("synthesized body is not valid JSON: %v (body=%s)", err, body)
+	}
+
+	if _, ok := decoded["age"].(float64); !ok {
+		t.Errorf("age = %#v (%T), want a JSON number", decoded["age"], decoded["age"])
+	}
+	if _, ok := decoded["active"].(bool); !ok {
+		t.Errorf("active = %#v (%T), want a JSON boolean", decoded["active"], decoded["active"])
+	}
+	if _, ok := decoded["name"].(string); !ok {
+		t.Errorf("name = %#v (%T), want a JSON string", decoded["name"], decoded["name"])
+	}
+}
+
+func TestSynthesizeOpenAPIBodyFallsBackToStringForMismatchedExample(t *testing.T) {
+	// A spec can declare "integer" but give a non-numeric example/default;
+	// that must still produce valid JSON rather than an unquoted non-literal.
+	reqBody := map[string]interface{}{
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"properties": map[string]interface{}{
+						"age": map[string]interface{}{"type": "integer", "example": "N/A"},
+					},
+				},
+			},
+		},
+	}
+
+	body, _ := synthesizeOpenAPIBody(reqBody)
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("synthesized body is not valid JSON: %v (body=%s)", err, body)
+	}
+	if got, ok := decoded["age"].(string); !ok || got != "N/A" {
+		t.Errorf("age = %#v, want the string \"N/A\" quoted as a fallback", decoded["age"])
+	}
+}
+
+func TestSynthesizeOpenAPIBodyRejectsNonFiniteNumbers(t *testing.T) {
+	// strconv.ParseFloat happily accepts "NaN"/"Infinity", but neither is
+	// valid JSON - these must still fall back to a quoted string.
+	for _, example := range []string{"NaN", "Infinity", "-Infinity"} {
+		reqBody := map[string]interface{}{
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{
+						"properties": map[string]interface{}{
+							"score": map[string]interface{}{"type": "number", "example": example},
+						},
+					},
+				},
+			},
+		}
+		body, _ := synthesizeOpenAPIBody(reqBody)
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(body, &decoded); err != nil {
+			t.Fatalf("example %q: synthesized body is not valid JSON: %v (body=%s)", example, err, body)
+		}
+		if got, ok := decoded["score"].(string); !ok || got != example {
+			t.Errorf("example %q: score = %#v, want it quoted as a string fallback", example, decoded["score"])
+		}
+	}
+}
+
+func TestSynthesizeOpenAPIBodyCanonicalizesLooseBooleans(t *testing.T) {
+	// A spec using "1"/"0" for a boolean must emit the canonical true/false,
+	// not a bare number that happens to be valid JSON but the wrong type.
+	reqBody := map[string]interface{}{
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"properties": map[string]interface{}{
+						"enabled": map[string]interface{}{"type": "boolean", "example": "1"},
+					},
+				},
+			},
+		},
+	}
+	body, _ := synthesizeOpenAPIBody(reqBody)
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		t.Fatalf("synthesized body is not valid JSON: %v (body=%s)", err, body)
+	}
+	if got, ok := decoded["enabled"].(bool); !ok || got != true {
+		t.Errorf("enabled = %#v (%T), want the boolean true", decoded["enabled"], decoded["enabled"])
+	}
+}
+
+func TestSplitRawHTTPRequest(t *testing.T) {
+	raw := []byte("POST /login HTTP/1.1\r\nHost: example.com\r\nContent-Type: application/json\r\n\r\n{\"a\":1}")
+	body, contentType := splitRawHTTPRequest(raw)
+	if string(body) != `{"a":1}` {
+		t.Errorf("body = %q, want %q", body, `{"a":1}`)
+	}
+	if contentType != "application/json" {
+		t.Errorf("contentType = %q, want application/json", contentType)
+	}
+}
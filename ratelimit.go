@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// hostLimiter hands out a per-host rate.Limiter, creating one lazily the
+// first time a host is seen. A zero rps disables limiting.
+type hostLimiter struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	rps      float64
+}
+
+func newHostLimiter(rps float64) *hostLimiter {
+	return &hostLimiter{limiters: make(map[string]*rate.Limiter), rps: rps}
+}
+
+func (h *hostLimiter) wait(rawURL string) {
+	if h.rps <= 0 {
+		return
+	}
+	host := rawURL
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	h.mu.Lock()
+	limiter, ok := h.limiters[host]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(h.rps), 1)
+		h.limiters[host] = limiter
+	}
+	h.mu.Unlock()
+
+	limiter.Wait(context.Background())
+}
+
+// backoffSchedule returns the jittered exponential backoff durations to use
+// between retry attempts, capped at a sane maximum.
+func backoffSchedule(attempt int) time.Duration {
+	base := time.Duration(1<<uint(attempt)) * 100 * time.Millisecond
+	max := 5 * time.Second
+	if base > max {
+		base = max
+	}
+	jitter := time.Duration(rand.Int63n(int64(base) / 2))
+	return base + jitter
+}
+
+// isRetryableStatus reports whether an HTTP status code warrants a retry.
+func isRetryableStatus(status int) bool {
+	return status == 429 || (status >= 500 && status <= 599)
+}
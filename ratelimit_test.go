@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsRetryableStatus(t *testing.T) {
+	cases := map[int]bool{
+		200: false,
+		404: false,
+		429: true,
+		500: true,
+		503: true,
+		599: true,
+		600: false,
+	}
+	for status, want := range cases {
+		if got := isRetryableStatus(status); got != want {
+			t.Errorf("isRetryableStatus(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestBackoffScheduleGrowsAndCaps(t *testing.T) {
+	if d := backoffSchedule(0); d < 100*time.Millisecond || d > 150*time.Millisecond {
+		t.Errorf("backoffSchedule(0) = %v, want in [100ms, 150ms)", d)
+	}
+	if d := backoffSchedule(10); d < 5*time.Second || d > 7500*time.Millisecond {
+		t.Errorf("backoffSchedule(10) = %v, want capped near 5s-7.5s", d)
+	}
+}
+
+func TestHostLimiterZeroRPSDoesNotBlock(t *testing.T) {
+	h := newHostLimiter(0)
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 100; i++ {
+			h.wait("http://example.com/x")
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("hostLimiter with rps=0 should never block")
+	}
+}
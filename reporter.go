@@ -0,0 +1,230 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Reporter streams completed Results to a destination as the scan runs.
+// Implementations must be safe to call Write sequentially from processSource's
+// single result-draining goroutine (no internal locking is required).
+type Reporter interface {
+	Write(Result) error
+	Close() error
+}
+
+// NewReporter builds the Reporter for the given format, writing to path.
+// Supported formats are "json", "jsonl" and "csv".
+func NewReporter(format, path string) (Reporter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("creating report file: %w", err)
+	}
+
+	switch format {
+	case "json":
+		return &jsonReporter{file: f, first: true}, nil
+	case "jsonl":
+		return &jsonlReporter{file: f}, nil
+	case "csv":
+		w := csv.NewWriter(f)
+		r := &csvReporter{file: f, writer: w}
+		if err := r.writeHeader(); err != nil {
+			f.Close()
+			return nil, err
+		}
+		return r, nil
+	default:
+		f.Close()
+		return nil, fmt.Errorf("unknown report format %q (want json, jsonl or csv)", format)
+	}
+}
+
+// reportRow is the JSON/CSV shape of a Result, kept separate from Result so
+// the wire format doesn't shift every time internal fields are added.
+type reportRow struct {
+	Endpoint     string   `json:"endpoint"`
+	Method       string   `json:"method"`
+	StatusCode1  int      `json:"status_code_1"`
+	StatusCode2  int      `json:"status_code_2"`
+	Size1        int64    `json:"size_1"`
+	Size2        int64    `json:"size_2"`
+	HeaderHash1  string   `json:"header_hash_1"`
+	HeaderHash2  string   `json:"header_hash_2"`
+	Similarity   float64  `json:"similarity"`
+	DiffLines    []string `json:"diff_lines,omitempty"`
+	RetryCount   int      `json:"retry_count"`
+	RateLimited  bool     `json:"rate_limited"`
+	Description1 string   `json:"description_1"`
+	Description2 string   `json:"description_2"`
+	Skipped      bool     `json:"skipped"`
+	Error        string   `json:"error,omitempty"`
+}
+
+func toRow(r Result) reportRow {
+	row := reportRow{
+		Endpoint:     r.Endpoint,
+		Method:       r.Method,
+		StatusCode1:  r.StatusCode1,
+		StatusCode2:  r.StatusCode2,
+		Size1:        r.Size1,
+		Size2:        r.Size2,
+		HeaderHash1:  r.HeaderHash1,
+		HeaderHash2:  r.HeaderHash2,
+		Similarity:   r.Similarity,
+		DiffLines:    r.DiffLines,
+		RetryCount:   r.RetryCount,
+		RateLimited:  r.RateLimited,
+		Description1: r.Description1,
+		Description2: r.Description2,
+		Skipped:      r.Skipped,
+	}
+	if r.Error != nil {
+		row.Error = r.Error.Error()
+	}
+	return row
+}
+
+// jsonReporter accumulates a single JSON array written at Close.
+type jsonReporter struct {
+	file  *os.File
+	first bool
+}
+
+func (r *jsonReporter) Write(result Result) error {
+	if r.first {
+		if _, err := r.file.WriteString("[\n"); err != nil {
+			return err
+		}
+		r.first = false
+	} else {
+		if _, err := r.file.WriteString(",\n"); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(toRow(result))
+	if err != nil {
+		return err
+	}
+	_, err = r.file.Write(data)
+	return err
+}
+
+func (r *jsonReporter) Close() error {
+	if r.first {
+		// Nothing was ever written.
+		if _, err := r.file.WriteString("[]\n"); err != nil {
+			return err
+		}
+		return r.file.Close()
+	}
+	if _, err := r.file.WriteString("\n]\n"); err != nil {
+		return err
+	}
+	return r.file.Close()
+}
+
+// jsonlReporter writes one JSON object per line as results complete.
+type jsonlReporter struct {
+	file *os.File
+}
+
+func (r *jsonlReporter) Write(result Result) error {
+	data, err := json.Marshal(toRow(result))
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = r.file.Write(data)
+	return err
+}
+
+func (r *jsonlReporter) Close() error {
+	return r.file.Close()
+}
+
+var csvHeader = []string{
+	"endpoint", "method", "status_code_1", "status_code_2", "size_1", "size_2",
+	"header_hash_1", "header_hash_2", "similarity", "diff_lines", "retry_count", "rate_limited",
+	"description_1", "description_2", "skipped", "error",
+}
+
+type csvReporter struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+func (r *csvReporter) writeHeader() error {
+	if err := r.writer.Write(csvHeader); err != nil {
+		return err
+	}
+	r.writer.Flush()
+	return r.writer.Error()
+}
+
+func (r *csvReporter) Write(result Result) error {
+	row := toRow(result)
+	record := []string{
+		row.Endpoint,
+		row.Method,
+		strconv.Itoa(row.StatusCode1),
+		strconv.Itoa(row.StatusCode2),
+		strconv.FormatInt(row.Size1, 10),
+		strconv.FormatInt(row.Size2, 10),
+		row.HeaderHash1,
+		row.HeaderHash2,
+		strconv.FormatFloat(row.Similarity, 'f', 4, 64),
+		strings.Join(row.DiffLines, " || "),
+		strconv.Itoa(row.RetryCount),
+		strconv.FormatBool(row.RateLimited),
+		row.Description1,
+		row.Description2,
+		strconv.FormatBool(row.Skipped),
+		row.Error,
+	}
+	if err := r.writer.Write(record); err != nil {
+		return err
+	}
+	r.writer.Flush()
+	return r.writer.Error()
+}
+
+func (r *csvReporter) Close() error {
+	r.writer.Flush()
+	if err := r.writer.Error(); err != nil {
+		return err
+	}
+	return r.file.Close()
+}
+
+// hashHeaders returns a short sha256 hex digest of the response headers,
+// canonicalized by sorting keys so the hash is stable regardless of the
+// order the server (or Go's transport) emitted them in.
+func hashHeaders(h http.Header) string {
+	if h == nil {
+		return ""
+	}
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte(':')
+		sb.WriteString(strings.Join(h[k], ","))
+		sb.WriteByte('\n')
+	}
+
+	sum := sha256.Sum256([]byte(sb.String()))
+	return fmt.Sprintf("%x", sum[:8])
+}
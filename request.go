@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxBodyBytes bounds how much of a response body we buffer for comparison,
+// so a huge endpoint can't blow up memory across thousands of goroutines.
+const maxBodyBytes = 512 * 1024
+
+// requestResult carries everything one side of a comparison produced,
+// including the raw wire dump when capture is enabled.
+type requestResult struct {
+	status      int
+	size        int64
+	headers     http.Header
+	body        []byte
+	retries     int
+	rateLimited bool
+	rawReq      []byte
+	rawResp     []byte
+}
+
+// bodyReader returns a fresh reader over body each time it's called, so the
+// live request and its capture dump never share one io.ReadCloser that
+// draining either side would exhaust for the other.
+func bodyReader(body []byte) io.Reader {
+	if len(body) == 0 {
+		return nil
+	}
+	return bytes.NewReader(body)
+}
+
+// makeRequest performs one request, retrying on transport errors and on
+// retryable status codes (429, 5xx) with jittered exponential backoff. It
+// reports the retry count and whether the final status still looked
+// rate-limited, so callers can distinguish "clean" from "probably throttled".
+// When capture is true, the raw request and response are dumped for replay.
+func makeRequest(httpClient *http.Client, ep Endpoint, profile AuthProfile, limiter *hostLimiter, retries int, capture bool) (requestResult, error) {
+	for attempt := 0; ; attempt++ {
+		if limiter != nil {
+			limiter.wait(ep.URL)
+		}
+
+		body := profile.RequestBody(ep)
+
+		req, reqErr := http.NewRequest(ep.Method, ep.URL, bodyReader(body))
+		if reqErr != nil {
+			return requestResult{retries: attempt}, reqErr
+		}
+		if ep.ContentType != "" {
+			req.Header.Set("Content-Type", ep.ContentType)
+		}
+		profile.Apply(req)
+
+		// Dump a clone of req (so the dump reflects whatever AuthProfile.Apply
+		// just merged into the URL/headers), but give the clone its own
+		// independent Body reader: Clone only shallow-copies Body, so dumping
+		// the clone as-is would drain the same io.ReadCloser req is about to
+		// send and leave httpClient.Do with nothing to write.
+		var rawReq []byte
+		if capture {
+			dumpReq := req.Clone(req.Context())
+			if len(body) > 0 {
+				dumpReq.Body = io.NopCloser(bytes.NewReader(body))
+			}
+			rawReq, _ = httputil.DumpRequestOut(dumpReq, true)
+		}
+
+		resp, doErr := httpClient.Do(req)
+		if doErr != nil {
+			if attempt < retries {
+				time.Sleep(backoffSchedule(attempt))
+				continue
+			}
+			return requestResult{retries: attempt}, fmt.Errorf("transport error after %d attempts: %w", attempt+1, doErr)
+		}
+
+		respBody, readErr := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+		resp.Body.Close()
+		if readErr != nil {
+			if attempt < retries {
+				time.Sleep(backoffSchedule(attempt))
+				continue
+			}
+			return requestResult{retries: attempt}, fmt.Errorf("reading body after %d attempts: %w", attempt+1, readErr)
+		}
+
+		var rawResp []byte
+		if capture {
+			resp.Body = io.NopCloser(bytes.NewReader(respBody))
+			rawResp, _ = httputil.DumpResponse(resp, true)
+		}
+
+		result := requestResult{
+			status:  resp.StatusCode,
+			size:    int64(len(respBody)),
+			headers: resp.Header,
+			body:    respBody,
+			retries: attempt,
+			rawReq:  rawReq,
+			rawResp: rawResp,
+		}
+
+		if isRetryableStatus(resp.StatusCode) {
+			if attempt < retries {
+				time.Sleep(backoffSchedule(attempt))
+				continue
+			}
+			result.rateLimited = true
+			return result, nil
+		}
+
+		return result, nil
+	}
+}
+
+func processEndpoint(httpClient *http.Client, ep Endpoint, profile1, profile2 AuthProfile, limiter *hostLimiter, retries int, capture bool) Result {
+	if strings.HasSuffix(ep.URL, ".js") || strings.HasSuffix(ep.URL, ".map") || strings.HasSuffix(ep.URL, ".svg") {
+		return Result{Endpoint: ep.URL, Method: ep.Method, Skipped: true, Error: fmt.Errorf("skipped static file")}
+	}
+
+	r1, err := makeRequest(httpClient, ep, profile1, limiter, retries, capture)
+	if err != nil {
+		return Result{Endpoint: ep.URL, Method: ep.Method, Error: err}
+	}
+
+	r2, err := makeRequest(httpClient, ep, profile2, limiter, retries, capture)
+	if err != nil {
+		return Result{Endpoint: ep.URL, Method: ep.Method, Error: err}
+	}
+
+	comparison := compareResponses(r1.body, r2.body)
+
+	return Result{
+		Endpoint:     ep.URL,
+		Method:       ep.Method,
+		StatusCode1:  r1.status,
+		StatusCode2:  r2.status,
+		Size1:        r1.size,
+		Size2:        r2.size,
+		HeaderHash1:  hashHeaders(r1.headers),
+		HeaderHash2:  hashHeaders(r2.headers),
+		Similarity:   comparison.Similarity,
+		DiffLines:    comparison.DiffLines,
+		RetryCount:   r1.retries + r2.retries,
+		RateLimited:  r1.rateLimited || r2.rateLimited,
+		RawReq1:      r1.rawReq,
+		RawResp1:     r1.rawResp,
+		RawReq2:      r2.rawReq,
+		RawResp2:     r2.rawResp,
+		Description1: profile1.String(),
+		Description2: profile2.String(),
+	}
+}
+
+// processSource loads endpoints from filename via LoadEndpoints (plain
+// text, HAR, OpenAPI/Swagger, or a Burp XML export) and runs every one of
+// them through profile1 vs profile2.
+func processSource(httpClient *http.Client, filename string, profile1, profile2 AuthProfile, reporter Reporter, threshold float64, workers int, rps float64, retries int, captureDir string) {
+	endpoints, err := LoadEndpoints(filename)
+	if err != nil {
+		fmt.Printf("Error loading endpoints: %v\n", err)
+		return
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+	limiter := newHostLimiter(rps)
+	capture := captureDir != ""
+
+	jobs := make(chan Endpoint)
+	results := make(chan Result)
+	var wg sync.WaitGroup
+
+	// Bounded worker pool, rather than two goroutines per endpoint, so a
+	// large endpoint file doesn't spawn tens of thousands of concurrent
+	// requests against the target.
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ep := range jobs {
+				results <- processEndpoint(httpClient, ep, profile1, profile2, limiter, retries, capture)
+			}
+		}()
+	}
+
+	go func() {
+		for _, ep := range endpoints {
+			jobs <- ep
+		}
+		close(jobs)
+	}()
+
+	// Close results channel when all goroutines are done
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Process results as they come in
+	total := len(endpoints)
+	count := 0
+	for result := range results {
+		count++
+		printProgress(count, total)
+
+		if reporter != nil {
+			if err := reporter.Write(result); err != nil {
+				fmt.Printf("\nError writing report: %v\n", err)
+			}
+		}
+
+		if result.Error != nil {
+			continue
+		}
+
+		// Only report if both status codes are 200 AND the responses are similar
+		// enough to be the same underlying page, rather than requiring an exact
+		// byte-size match.
+		if result.StatusCode1 == 200 && result.StatusCode2 == 200 && result.Similarity >= threshold {
+			// Clear the current line and print the match
+			fmt.Printf("\r\033[K") // Clear the entire line
+			printColored(colorGreen, fmt.Sprintf("Potential Auth Bypass Found!\n"))
+			printColored(colorGreen, fmt.Sprintf("Endpoint: %s [%s] (similarity %.1f%%)\n", result.Endpoint, result.Method, result.Similarity*100))
+			printColored(colorYellow, fmt.Sprintf("%s: %d (%d bytes)\n", result.Description1, result.StatusCode1, result.Size1))
+			printColored(colorYellow, fmt.Sprintf("%s: %d (%d bytes)\n", result.Description2, result.StatusCode2, result.Size2))
+			for _, line := range result.DiffLines {
+				printColored(colorBlue, fmt.Sprintf("  %s\n", line))
+			}
+			if result.RateLimited {
+				printColored(colorYellow, "Warning: responses still looked rate-limited after retries; treat this result with caution\n")
+			}
+			if capture {
+				if err := writeCapture(captureDir, result); err != nil {
+					fmt.Printf("Error writing capture: %v\n", err)
+				}
+			}
+			fmt.Printf("\n") // Add spacing between matches
+			// Print a new progress bar
+			printProgress(count, total)
+		}
+	}
+	fmt.Printf("\r\033[K") // Clear the final progress bar
+	fmt.Printf("Done.\n")
+
+	if reporter != nil {
+		if err := reporter.Close(); err != nil {
+			fmt.Printf("Error closing report: %v\n", err)
+		}
+	}
+}
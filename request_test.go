@@ -0,0 +1,79 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestMakeRequestCaptureDoesNotDrainBody guards against req.Clone sharing a
+// Body reader with the dump: with capture enabled, a POST with a body must
+// still reach the server intact.
+func TestMakeRequestCaptureDoesNotDrainBody(t *testing.T) {
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ep := Endpoint{URL: server.URL, Method: "POST", Body: []byte(`{"a":1}`), ContentType: "application/json"}
+
+	result, err := makeRequest(server.Client(), ep, AuthProfile{}, nil, 0, true)
+	if err != nil {
+		t.Fatalf("makeRequest returned error: %v", err)
+	}
+	if gotBody != `{"a":1}` {
+		t.Errorf("server saw body %q, want %q (capture must not drain the real request's body)", gotBody, `{"a":1}`)
+	}
+	if len(result.rawReq) == 0 {
+		t.Errorf("expected a non-empty raw request dump when capture is enabled")
+	}
+}
+
+// TestMakeRequestCaptureReflectsProfileQuery guards against the dump request
+// being built from ep.URL before AuthProfile.Apply merges in query params:
+// the captured rawReq must show the same URL that was actually sent.
+func TestMakeRequestCaptureReflectsProfileQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ep := Endpoint{URL: server.URL + "/path", Method: "GET"}
+	profile := AuthProfile{Query: map[string]string{"api_key": "s3cr3t"}}
+
+	result, err := makeRequest(server.Client(), ep, profile, nil, 0, true)
+	if err != nil {
+		t.Fatalf("makeRequest returned error: %v", err)
+	}
+	if !strings.Contains(string(result.rawReq), "api_key=s3cr3t") {
+		t.Errorf("captured raw request %q does not contain the query param merged by AuthProfile.Apply", result.rawReq)
+	}
+}
+
+// TestMakeRequestUsesProfileBodyTemplate guards against BodyTemplate being
+// silently ignored: when a profile sets one, it must override the
+// endpoint's own body on the wire.
+func TestMakeRequestUsesProfileBodyTemplate(t *testing.T) {
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ep := Endpoint{URL: server.URL, Method: "POST", Body: []byte(`{"from":"endpoint"}`)}
+	profile := AuthProfile{BodyTemplate: `{"from":"template"}`}
+
+	if _, err := makeRequest(server.Client(), ep, profile, nil, 0, false); err != nil {
+		t.Fatalf("makeRequest returned error: %v", err)
+	}
+	if gotBody != `{"from":"template"}` {
+		t.Errorf("server saw body %q, want the profile's BodyTemplate %q", gotBody, `{"from":"template"}`)
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Regexes for volatile tokens that change between otherwise-identical
+// responses (timestamps, nonces, CSRF tokens) and would otherwise sink the
+// similarity score even when the two responses are the "same" page.
+var (
+	uuidRe      = regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+	isoTimeRe   = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+	csrfTokenRe = regexp.MustCompile(`(?i)(name=["']?(csrf[_-]?token|_token|authenticity_token)["']?[^>]*value=["'][^"']*["'])`)
+)
+
+// stripVolatile replaces tokens that are expected to differ request-to-request
+// (UUIDs, ISO timestamps, CSRF hidden inputs) with a fixed placeholder so they
+// don't register as a content difference.
+func stripVolatile(body string) string {
+	body = uuidRe.ReplaceAllString(body, "UUID")
+	body = isoTimeRe.ReplaceAllString(body, "TIMESTAMP")
+	body = csrfTokenRe.ReplaceAllString(body, "CSRF_FIELD")
+	return body
+}
+
+// levenshteinSimilarity returns a 0..1 score: 1 means identical, computed as
+// 1 - (edit distance / length of the longer string).
+func levenshteinSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	maxLen := len(a)
+	if len(b) > maxLen {
+		maxLen = len(b)
+	}
+	if maxLen == 0 {
+		return 1
+	}
+	return 1 - float64(levenshteinDistance(a, b))/float64(maxLen)
+}
+
+func levenshteinDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			del := prev[j] + 1
+			ins := curr[j-1] + 1
+			sub := prev[j-1] + cost
+			min := del
+			if ins < min {
+				min = ins
+			}
+			if sub < min {
+				min = sub
+			}
+			curr[j] = min
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// tokenize splits on anything that isn't a letter or digit, for Jaccard and
+// simhash token sets.
+func tokenize(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+}
+
+// jaccardSimilarity computes the token-set Jaccard index of two bodies.
+func jaccardSimilarity(a, b string) float64 {
+	setA := map[string]struct{}{}
+	for _, t := range tokenize(a) {
+		setA[t] = struct{}{}
+	}
+	setB := map[string]struct{}{}
+	for _, t := range tokenize(b) {
+		setB[t] = struct{}{}
+	}
+	if len(setA) == 0 && len(setB) == 0 {
+		return 1
+	}
+	intersection := 0
+	for t := range setA {
+		if _, ok := setB[t]; ok {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+	if union == 0 {
+		return 1
+	}
+	return float64(intersection) / float64(union)
+}
+
+// simhash computes a 64-bit fuzzy hash over the token set of a body, in the
+// style of Charikar simhash / ssdeep: similar inputs produce hashes with a
+// small Hamming distance.
+func simhash(s string) uint64 {
+	var weights [64]int
+	for _, tok := range tokenize(s) {
+		h := fnv64a(tok)
+		for bit := 0; bit < 64; bit++ {
+			if h&(1<<uint(bit)) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+	var hash uint64
+	for bit := 0; bit < 64; bit++ {
+		if weights[bit] > 0 {
+			hash |= 1 << uint(bit)
+		}
+	}
+	return hash
+}
+
+func fnv64a(s string) uint64 {
+	const offset64 = 14695981039346656037
+	const prime64 = 1099511628211
+	h := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime64
+	}
+	return h
+}
+
+func hammingDistance(a, b uint64) int {
+	x := a ^ b
+	count := 0
+	for x != 0 {
+		count++
+		x &= x - 1
+	}
+	return count
+}
+
+// simhashSimilarity turns a Hamming distance over 64 bits into a 0..1 score.
+func simhashSimilarity(a, b string) float64 {
+	dist := hammingDistance(simhash(a), simhash(b))
+	return 1 - float64(dist)/64
+}
+
+// Comparison is the result of comparing two response bodies.
+type Comparison struct {
+	Similarity float64
+	DiffLines  []string
+}
+
+const maxDiffLines = 5
+
+// maxLevenshteinBytes bounds the prefix fed to the O(n*m) Levenshtein edit
+// distance, so its cost stays bounded regardless of how close to
+// maxBodyBytes the response actually is.
+const maxLevenshteinBytes = 4096
+
+// levenshteinSizeRatio is how close two bodies' lengths must be, relative to
+// the longer one, before it's worth paying for a full edit-distance pass at
+// all; bodies that already differ by more than this are not near-duplicates
+// and the cheaper Jaccard/simhash signals are enough.
+const levenshteinSizeRatio = 0.2
+
+// compareResponses scores how similar two response bodies are after
+// stripping known-volatile tokens, combining independent signals (token-set
+// Jaccard, simhash, and - when the bodies are close enough in size to be
+// worth it - normalized Levenshtein over a bounded prefix) into a single
+// score, and collects the first lines that differ for side-by-side display.
+func compareResponses(body1, body2 []byte) Comparison {
+	a := stripVolatile(string(body1))
+	b := stripVolatile(string(body2))
+
+	scores := []float64{jaccardSimilarity(a, b), simhashSimilarity(a, b)}
+	if lev, ok := boundedLevenshteinSimilarity(a, b); ok {
+		scores = append(scores, lev)
+	}
+
+	var sum float64
+	for _, s := range scores {
+		sum += s
+	}
+
+	return Comparison{
+		Similarity: sum / float64(len(scores)),
+		DiffLines:  diffLines(a, b),
+	}
+}
+
+// boundedLevenshteinSimilarity runs Levenshtein only when the two bodies are
+// already close in size (a cheap pre-check for "might be a near-duplicate"),
+// and only over a bounded prefix of each, so a single comparison can never
+// cost more than maxLevenshteinBytes^2 regardless of response size.
+func boundedLevenshteinSimilarity(a, b string) (float64, bool) {
+	if !sizesClose(len(a), len(b)) {
+		return 0, false
+	}
+	return levenshteinSimilarity(truncateForLevenshtein(a), truncateForLevenshtein(b)), true
+}
+
+func sizesClose(lenA, lenB int) bool {
+	if lenA == 0 && lenB == 0 {
+		return true
+	}
+	max := lenA
+	if lenB > max {
+		max = lenB
+	}
+	diff := lenA - lenB
+	if diff < 0 {
+		diff = -diff
+	}
+	return float64(diff)/float64(max) <= levenshteinSizeRatio
+}
+
+func truncateForLevenshtein(s string) string {
+	if len(s) > maxLevenshteinBytes {
+		return s[:maxLevenshteinBytes]
+	}
+	return s
+}
+
+// diffLines returns up to maxDiffLines "<1> ... | <2> ..." pairs for lines
+// that differ at the same position, for a quick side-by-side look.
+func diffLines(a, b string) []string {
+	linesA := strings.Split(a, "\n")
+	linesB := strings.Split(b, "\n")
+
+	max := len(linesA)
+	if len(linesB) > max {
+		max = len(linesB)
+	}
+
+	var diffs []string
+	for i := 0; i < max && len(diffs) < maxDiffLines; i++ {
+		var la, lb string
+		if i < len(linesA) {
+			la = linesA[i]
+		}
+		if i < len(linesB) {
+			lb = linesB[i]
+		}
+		if la != lb {
+			diffs = append(diffs, fmt.Sprintf("L%d: %s | %s", i+1, truncate(la, 80), truncate(lb, 80)))
+		}
+	}
+	return diffs
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "…"
+}
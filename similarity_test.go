@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLevenshteinSimilarity(t *testing.T) {
+	if got := levenshteinSimilarity("abc", "abc"); got != 1 {
+		t.Errorf("identical strings: got %v, want 1", got)
+	}
+	if got := levenshteinSimilarity("abc", "abd"); got <= 0 || got >= 1 {
+		t.Errorf("one-char diff: got %v, want in (0,1)", got)
+	}
+	if got := levenshteinSimilarity("", ""); got != 1 {
+		t.Errorf("both empty: got %v, want 1", got)
+	}
+}
+
+func TestJaccardSimilarity(t *testing.T) {
+	if got := jaccardSimilarity("the quick fox", "the quick fox"); got != 1 {
+		t.Errorf("identical: got %v, want 1", got)
+	}
+	if got := jaccardSimilarity("a b c", "x y z"); got != 0 {
+		t.Errorf("disjoint token sets: got %v, want 0", got)
+	}
+}
+
+func TestSimhashSimilarity(t *testing.T) {
+	if got := simhashSimilarity("hello world", "hello world"); got != 1 {
+		t.Errorf("identical: got %v, want 1", got)
+	}
+	close := simhashSimilarity("hello world foo bar", "hello world foo baz")
+	far := simhashSimilarity("hello world foo bar", "completely unrelated text here")
+	if close <= far {
+		t.Errorf("expected near-duplicate (%v) to score higher than unrelated text (%v)", close, far)
+	}
+}
+
+func TestBoundedLevenshteinSimilaritySkipsDissimilarSizes(t *testing.T) {
+	small := "x"
+	large := strings.Repeat("y", 10000)
+	if _, ok := boundedLevenshteinSimilarity(small, large); ok {
+		t.Errorf("expected bodies with very different sizes to skip full Levenshtein")
+	}
+}
+
+func TestBoundedLevenshteinSimilarityBoundedCost(t *testing.T) {
+	a := strings.Repeat("a", maxBodyBytes)
+	b := strings.Repeat("a", maxBodyBytes-4) + "bbbb"
+
+	done := make(chan struct{})
+	go func() {
+		boundedLevenshteinSimilarity(a, b)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("boundedLevenshteinSimilarity did not return quickly for near-max-size bodies")
+	}
+}
+
+func TestCompareResponses(t *testing.T) {
+	c := compareResponses([]byte("same body"), []byte("same body"))
+	if c.Similarity != 1 {
+		t.Errorf("identical bodies: got similarity %v, want 1", c.Similarity)
+	}
+
+	c2 := compareResponses([]byte("access denied"), []byte("completely different page content"))
+	if c2.Similarity >= c.Similarity {
+		t.Errorf("expected dissimilar bodies to score lower than identical ones")
+	}
+}
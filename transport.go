@@ -0,0 +1,37 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// buildClient constructs the http.Client used for every request, honoring
+// -proxy (so traffic can be routed through Burp/ZAP for replay), -insecure
+// (to trust MITM proxy CAs), and -timeout. It replaces the old package-level
+// client var so these flags can actually take effect.
+func buildClient(proxyURLStr string, insecure bool, timeout time.Duration) (*http.Client, error) {
+	transport := &http.Transport{
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+		DisableCompression:  true,
+		MaxConnsPerHost:     10,
+		MaxIdleConnsPerHost: 10,
+	}
+
+	if proxyURLStr != "" {
+		proxyURL, err := url.Parse(proxyURLStr)
+		if err != nil {
+			return nil, fmt.Errorf("parsing -proxy: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	return &http.Client{Timeout: timeout, Transport: transport}, nil
+}